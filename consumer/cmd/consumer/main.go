@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -15,24 +14,28 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
-func initTracer() (*sdktrace.TracerProvider, error) {
-	jaegerHost := os.Getenv("JAEGER_HOST")
-	if jaegerHost == "" {
-		return nil, errors.New(`JAEGER_HOST is not set`)
+// initTracer sets up an OTLP/gRPC exporter. Jaeger dropped native support in
+// favor of OTLP, so this also works with a Jaeger collector that has its
+// OTLP receiver enabled. The endpoint is read from
+// OTEL_EXPORTER_OTLP_ENDPOINT as a plain host:port (no URL scheme),
+// defaulting to the standard local collector port.
+func initTracer(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "localhost:4317"
 	}
 
-	jaegerPort := os.Getenv("JAEGER_PORT")
-	if jaegerPort == "" {
-		jaegerPort = "14268"
-	}
-	jaegerApiEndpoint := fmt.Sprintf("http://%s:%s/api/traces", jaegerHost, jaegerPort)
-	exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(jaegerApiEndpoint)))
+	exp, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -45,11 +48,20 @@ func initTracer() (*sdktrace.TracerProvider, error) {
 		)),
 	)
 	otel.SetTracerProvider(tp)
+
+	// Without this, the global propagator defaults to a no-op composite and
+	// redisconsumer.extractPropagatedContext never finds a traceparent field
+	// to extract, so consumer spans never join the producer's trace.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
 	return tp, nil
 }
 
 func main() {
-	tp, err := initTracer()
+	ctx := context.Background()
+
+	tp, err := initTracer(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -110,7 +122,6 @@ func main() {
 		log.Fatalf("new consumer: %v", err)
 	}
 
-	ctx := context.Background()
 	if err := consumer.Start(ctx); err != nil {
 		log.Fatalf("start: %v", err)
 	}
@@ -0,0 +1,193 @@
+package redisconsumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeScaleClient stubs only the calls evaluateScale/consumeLoop make:
+// XPending to report a pending count, and XReadGroup to block until its
+// context is canceled (so a worker spawned by a scale-up has something to
+// do besides busy-spin, and exits cleanly when the test tears down).
+type fakeScaleClient struct {
+	redis.UniversalClient
+	pending int64
+}
+
+func (f *fakeScaleClient) XPending(ctx context.Context, stream, group string) *redis.XPendingCmd {
+	cmd := redis.NewXPendingCmd(ctx)
+	cmd.SetVal(&redis.XPending{Count: f.pending})
+	return cmd
+}
+
+func (f *fakeScaleClient) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	cmd := redis.NewXStreamSliceCmd(ctx)
+	<-ctx.Done()
+	cmd.SetErr(ctx.Err())
+	return cmd
+}
+
+func newTestConsumer(t *testing.T, client redis.UniversalClient, cfg ConsumerConfig) *Consumer {
+	t.Helper()
+	m, err := newMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("newMetrics: %v", err)
+	}
+	return &Consumer{
+		cfg:           cfg,
+		client:        client,
+		metrics:       m,
+		streamWorkers: make(map[string]*streamWorkers),
+	}
+}
+
+func TestEvaluateScaleUp(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ConsumerConfig{
+		Group:             "g",
+		ConsumerName:      "c1",
+		ReadCount:         10,
+		HighWatermark:     100,
+		LowWatermark:      10,
+		ScaleUpAfter:      3,
+		ScaleDownCooldown: time.Minute,
+		MinConcurrency:    1,
+		MaxConcurrency:    2,
+	}
+	client := &fakeScaleClient{pending: 1000}
+	c := newTestConsumer(t, client, cfg)
+
+	sw := &streamWorkers{cancels: make(map[int]context.CancelFunc)}
+	sw.current = 1
+	sw.readCount = cfg.ReadCount
+
+	// Fewer than ScaleUpAfter consecutive over-watermark intervals must not
+	// scale up yet.
+	c.evaluateScale(ctx, "s", sw)
+	c.evaluateScale(ctx, "s", sw)
+	if got := sw.current; got != 1 {
+		t.Fatalf("scaled up too early: current=%d", got)
+	}
+
+	// The third consecutive interval crosses ScaleUpAfter.
+	c.evaluateScale(ctx, "s", sw)
+	if got := sw.current; got != 2 {
+		t.Fatalf("expected scale up to 2 workers, got %d", got)
+	}
+	if sw.highRun != 0 {
+		t.Fatalf("expected highRun reset after scaling up, got %d", sw.highRun)
+	}
+
+	// Already at MaxConcurrency: further over-watermark intervals must not
+	// grow the pool past the ceiling.
+	for i := 0; i < cfg.ScaleUpAfter; i++ {
+		c.evaluateScale(ctx, "s", sw)
+	}
+	if got := sw.current; got != 2 {
+		t.Fatalf("scaled past MaxConcurrency: current=%d", got)
+	}
+
+	for id, cancelWorker := range sw.cancels {
+		cancelWorker()
+		delete(sw.cancels, id)
+	}
+	c.wg.Wait()
+}
+
+func TestEvaluateScaleDown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ConsumerConfig{
+		Group:             "g",
+		ConsumerName:      "c1",
+		ReadCount:         10,
+		HighWatermark:     100,
+		LowWatermark:      10,
+		ScaleUpAfter:      1,
+		ScaleDownCooldown: time.Minute,
+		MinConcurrency:    1,
+		MaxConcurrency:    3,
+	}
+	client := &fakeScaleClient{pending: 1}
+	c := newTestConsumer(t, client, cfg)
+
+	sw := &streamWorkers{cancels: make(map[int]context.CancelFunc)}
+	sw.current = 2
+	sw.readCount = cfg.ReadCount
+	sw.cancels[0] = func() {}
+	sw.cancels[1] = func() {}
+
+	// First under-watermark interval only starts the cooldown clock.
+	c.evaluateScale(ctx, "s", sw)
+	if got := sw.current; got != 2 {
+		t.Fatalf("scaled down before cooldown elapsed: current=%d", got)
+	}
+	if sw.lowSince.IsZero() {
+		t.Fatal("expected lowSince to be recorded")
+	}
+
+	// Backdate lowSince past the cooldown so the next interval scales down
+	// without sleeping in the test.
+	sw.mu.Lock()
+	sw.lowSince = time.Now().Add(-cfg.ScaleDownCooldown - time.Second)
+	sw.mu.Unlock()
+
+	c.evaluateScale(ctx, "s", sw)
+	if got := sw.current; got != 1 {
+		t.Fatalf("expected scale down to 1 worker, got %d", got)
+	}
+
+	// At MinConcurrency: further under-watermark intervals must not shrink
+	// the pool past the floor.
+	sw.mu.Lock()
+	sw.lowSince = time.Now().Add(-cfg.ScaleDownCooldown - time.Second)
+	sw.mu.Unlock()
+	c.evaluateScale(ctx, "s", sw)
+	if got := sw.current; got != 1 {
+		t.Fatalf("scaled below MinConcurrency: current=%d", got)
+	}
+}
+
+func TestEvaluateScaleMidRangeResetsCounters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ConsumerConfig{
+		Group:             "g",
+		ConsumerName:      "c1",
+		ReadCount:         10,
+		HighWatermark:     100,
+		LowWatermark:      10,
+		ScaleUpAfter:      2,
+		ScaleDownCooldown: time.Minute,
+		MinConcurrency:    1,
+		MaxConcurrency:    2,
+	}
+	client := &fakeScaleClient{pending: 50}
+	c := newTestConsumer(t, client, cfg)
+
+	sw := &streamWorkers{cancels: make(map[int]context.CancelFunc)}
+	sw.current = 1
+	sw.readCount = cfg.ReadCount
+	sw.highRun = 1
+	sw.lowSince = time.Now()
+
+	c.evaluateScale(ctx, "s", sw)
+
+	if sw.highRun != 0 {
+		t.Fatalf("expected highRun reset in mid-range, got %d", sw.highRun)
+	}
+	if !sw.lowSince.IsZero() {
+		t.Fatal("expected lowSince reset in mid-range")
+	}
+	if got := sw.current; got != 1 {
+		t.Fatalf("mid-range interval must not change worker count, got %d", got)
+	}
+}
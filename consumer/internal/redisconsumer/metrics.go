@@ -0,0 +1,163 @@
+package redisconsumer
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics holds the Prometheus collectors a Consumer reports against,
+// labeled by stream and consumer so a shared registry can distinguish
+// multiple Consumers.
+type metrics struct {
+	messagesRead      *prometheus.CounterVec
+	messagesProcessed *prometheus.CounterVec
+	messagesAcked     *prometheus.CounterVec
+	handlerErrors     *prometheus.CounterVec
+	reclaimed         *prometheus.CounterVec
+	deadLettered      *prometheus.CounterVec
+	handlerLatency    *prometheus.HistogramVec
+	pending           *prometheus.GaugeVec
+	lagMillis         *prometheus.GaugeVec
+	scaleEvents       *prometheus.CounterVec
+}
+
+// registerCounterVec registers cv against reg, or, if a collector with the
+// same name is already registered (as happens when multiple Consumers share
+// a MetricsRegistry), returns the existing one instead.
+func registerCounterVec(reg prometheus.Registerer, cv *prometheus.CounterVec) (*prometheus.CounterVec, error) {
+	if err := reg.Register(cv); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		existing, ok := are.ExistingCollector.(*prometheus.CounterVec)
+		if !ok {
+			return nil, fmt.Errorf("redisconsumer: metric already registered under a different type: %w", err)
+		}
+		return existing, nil
+	}
+	return cv, nil
+}
+
+func registerGaugeVec(reg prometheus.Registerer, gv *prometheus.GaugeVec) (*prometheus.GaugeVec, error) {
+	if err := reg.Register(gv); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		existing, ok := are.ExistingCollector.(*prometheus.GaugeVec)
+		if !ok {
+			return nil, fmt.Errorf("redisconsumer: metric already registered under a different type: %w", err)
+		}
+		return existing, nil
+	}
+	return gv, nil
+}
+
+func registerHistogramVec(reg prometheus.Registerer, hv *prometheus.HistogramVec) (*prometheus.HistogramVec, error) {
+	if err := reg.Register(hv); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		existing, ok := are.ExistingCollector.(*prometheus.HistogramVec)
+		if !ok {
+			return nil, fmt.Errorf("redisconsumer: metric already registered under a different type: %w", err)
+		}
+		return existing, nil
+	}
+	return hv, nil
+}
+
+// newMetrics builds the Consumer's collectors and registers them against
+// reg, reusing any collector already registered under the same name so
+// multiple Consumers can safely share a MetricsRegistry.
+func newMetrics(reg prometheus.Registerer) (*metrics, error) {
+	labels := []string{"stream", "consumer"}
+	m := &metrics{}
+	var err error
+
+	m.messagesRead, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_messages_read_total",
+		Help: "Messages read from a stream via XREADGROUP.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.messagesProcessed, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_messages_processed_total",
+		Help: "Messages handed to the handler.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.messagesAcked, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_messages_acked_total",
+		Help: "Messages acknowledged via XACK.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.handlerErrors, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_handler_errors_total",
+		Help: "Handler invocations that ended in a nack with a non-empty reason (for a plain Handler, a returned error).",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.reclaimed, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_reclaimed_total",
+		Help: "Pending entries reclaimed from other consumers via XAUTOCLAIM.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.deadLettered, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_dead_lettered_total",
+		Help: "Messages routed to the dead-letter stream.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.handlerLatency, err = registerHistogramVec(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "redisconsumer_handler_duration_seconds",
+		Help:    "Handler call latency.",
+		Buckets: prometheus.DefBuckets,
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.pending, err = registerGaugeVec(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "redisconsumer_pending",
+		Help: "Current pending entry count for the stream/group, sampled via XPENDING.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.lagMillis, err = registerGaugeVec(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "redisconsumer_lag_milliseconds",
+		Help: "Approximate consumer lag: last-generated-id minus the group's last-delivered-id, in milliseconds.",
+	}, labels))
+	if err != nil {
+		return nil, err
+	}
+
+	m.scaleEvents, err = registerCounterVec(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redisconsumer_scale_events_total",
+		Help: "Autoscaler worker pool changes, labeled by direction (up/down).",
+	}, []string{"stream", "consumer", "direction"}))
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
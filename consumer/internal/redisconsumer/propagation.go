@@ -0,0 +1,41 @@
+package redisconsumer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// messageCarrier adapts a stream message's Values map to otel's
+// TextMapCarrier so a producer's span context, injected as ordinary
+// traceparent/tracestate fields, can be read back out of it.
+type messageCarrier map[string]interface{}
+
+func (c messageCarrier) Get(key string) string {
+	v, ok := c[key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func (c messageCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c messageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// extractPropagatedContext returns ctx with its span context replaced by
+// whatever W3C TraceContext the producer injected into values (see
+// Inject in the companion redisproducer package), so the span processMessage
+// starts becomes a child of the producer's span instead of a new trace root.
+func extractPropagatedContext(ctx context.Context, values map[string]interface{}) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, messageCarrier(values))
+}
@@ -0,0 +1,36 @@
+package redisconsumer
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Companion to redisproducer's TestInjectWritesTraceparent: proves
+// extractPropagatedContext actually reads a traceparent field back into a
+// matching span context, which only happens once a real TextMapPropagator
+// (not the SDK's no-op default) is installed.
+func TestExtractPropagatedContextReadsTraceparent(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	values := map[string]interface{}{
+		"traceparent": "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01",
+	}
+
+	ctx := extractPropagatedContext(context.Background(), values)
+	sc := trace.SpanContextFromContext(ctx)
+
+	if !sc.IsValid() {
+		t.Fatal("expected a valid span context extracted from the injected traceparent")
+	}
+	if got, want := sc.TraceID().String(), "0102030405060708090a0b0c0d0e0f10"; got != want {
+		t.Fatalf("trace id = %s, want %s", got, want)
+	}
+	if got, want := sc.SpanID().String(), "0102030405060708"; got != want {
+		t.Fatalf("span id = %s, want %s", got, want)
+	}
+}
@@ -2,27 +2,108 @@ package redisconsumer
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// Handler is the original message-handling signature: a non-nil error nacks
+// the message (it is left pending for the reclaimer) while a nil error acks
+// it when AckOnSuccess is set. Prefer ResultHandler for explicit control.
 type Handler func(ctx context.Context, stream string, msg redis.XMessage) error
 
+// AckAction is the disposition a ResultHandler chooses for a message.
+type AckAction int
+
+const (
+	// AckMessage acknowledges the message; it will not be redelivered.
+	AckMessage AckAction = iota
+	// NackMessage leaves the message pending so the PEL reclaimer can
+	// redeliver it once MinIdleTime has elapsed.
+	NackMessage
+	// RetryMessage re-XADDs the message (with an incremented x-attempt
+	// header) after RetryAfter, then acks the original delivery.
+	RetryMessage
+	// DeadLetterMessage routes the message to DeadLetterStream and acks
+	// the original delivery.
+	DeadLetterMessage
+)
+
+// HandlerResult is a ResultHandler's explicit ack/nack/retry/dead-letter
+// decision for one message.
+type HandlerResult struct {
+	Action     AckAction
+	RetryAfter time.Duration // only meaningful for RetryMessage
+	Reason     string        // logged on Nack; stored on DeadLetter
+}
+
+// ResultHandler is the richer alternative to Handler: it returns an explicit
+// HandlerResult instead of a plain error, so callers can choose per-message
+// whether to ack, leave the message pending, retry it, or dead-letter it.
+type ResultHandler func(ctx context.Context, stream string, msg redis.XMessage) HandlerResult
+
+// BatchHandler is an opt-in alternative to Handler/ResultHandler: it
+// receives the full slice of messages from one XREADGROUP call and returns
+// one HandlerResult per message (same order), so acks for the batch can be
+// pipelined into a single XACK instead of one round trip per message.
+type BatchHandler func(ctx context.Context, stream string, msgs []redis.XMessage) []HandlerResult
+
+// ClientMode selects which Redis topology NewConsumer dials when no
+// pre-built Client is supplied in ConsumerConfig.
+type ClientMode int
+
+const (
+	// Standalone dials a single Redis node via Addr. This is the default.
+	Standalone ClientMode = iota
+	// Sentinel dials a Sentinel-monitored master via MasterName and SentinelAddrs.
+	Sentinel
+	// Cluster dials a Redis Cluster via ClusterAddrs.
+	Cluster
+)
+
 type ConsumerConfig struct {
-	Addr         string        // Redis address (e.g., "localhost:6379")
-	Password     string        // optional
-	User         string        // optional
-	DB           int           // Redis DB number
+	// Client, when set, is used as-is and Mode/Addr/Sentinel*/Cluster* below
+	// are ignored. Use this to share a client across consumers or to inject
+	// a test double.
+	Client redis.UniversalClient
+	// Mode selects the topology NewConsumer dials when Client is nil.
+	// Defaults to Standalone.
+	Mode ClientMode
+
+	Addr     string // Redis address for Standalone mode (e.g., "localhost:6379")
+	Password string // optional
+	User     string // optional
+	DB       int    // Redis DB number (Standalone and Sentinel only; Cluster has no concept of DB)
+
+	MasterName       string   // Sentinel: name of the monitored master
+	SentinelAddrs    []string // Sentinel: addresses of the sentinel nodes
+	SentinelPassword string   // Sentinel: password for the sentinel nodes themselves, if any
+
+	ClusterAddrs  []string // Cluster: seed node addresses
+	RouteRandomly bool     // Cluster: route read-only commands to random replicas
+
+	TLSConfig *tls.Config // optional, applies to any mode
+
+	// Streams are the Redis stream keys to consume. In Cluster mode, a
+	// stream and anything that must live on the same shard as it (e.g. its
+	// DeadLetterStream) should share a hash tag, e.g. "{orders}:events" and
+	// "{orders}:dlq", so XADD/XACK/XCLAIM for the pair always hit one shard.
 	Streams      []string      // List of Redis stream keys
 	Group        string        // Consumer group name
 	ConsumerName string        // Consumer instance name
@@ -32,26 +113,225 @@ type ConsumerConfig struct {
 	HealthAddr   string        // HTTP health check (e.g., ":8082")
 	AckOnSuccess bool          // Automatically acknowledge on success
 	Tracer       trace.Tracer
+
+	// ReclaimInterval enables the PEL reclaimer when > 0: on each tick the
+	// consumer scans each stream's pending entries list via XAUTOCLAIM and
+	// takes over messages abandoned by other consumers.
+	ReclaimInterval time.Duration
+	// MinIdleTime is how long a pending entry must have been idle before
+	// it is eligible for reclaim. Defaults to 30s when ReclaimInterval > 0.
+	MinIdleTime time.Duration
+	// MaxDeliveries is the delivery count above which a reclaimed message is
+	// routed to DeadLetterStream instead of being retried. 0 disables the check.
+	MaxDeliveries int64
+	// DeadLetterStream is the stream poison messages are XADD'ed to once
+	// MaxDeliveries is exceeded. Dead-lettering is disabled when empty.
+	DeadLetterStream string
+
+	// MetricsRegistry, when set, is used to register and serve this
+	// Consumer's Prometheus metrics instead of a private per-consumer
+	// registry. Set this to merge these metrics into an existing registry.
+	MetricsRegistry *prometheus.Registry
+	// MetricsSampleInterval controls how often the pending-count and
+	// consumer-lag gauges are refreshed via XPENDING/XINFO. Defaults to 15s.
+	MetricsSampleInterval time.Duration
+
+	// MaxConcurrency enables the autoscaler when > 0: each stream's worker
+	// pool starts at Concurrency and is scaled between MinConcurrency and
+	// MaxConcurrency based on pending count.
+	MaxConcurrency int
+	// MinConcurrency is the autoscaler's floor. Defaults to Concurrency.
+	MinConcurrency int
+	// ScaleInterval is how often the autoscaler evaluates pending count.
+	// Defaults to 10s.
+	ScaleInterval time.Duration
+	// HighWatermark is the pending count above which the autoscaler starts
+	// counting toward scaling up a stream's worker pool.
+	HighWatermark int64
+	// LowWatermark is the pending count below which the autoscaler starts
+	// counting toward scaling a stream's worker pool down.
+	LowWatermark int64
+	// ScaleUpAfter is how many consecutive over-HighWatermark intervals must
+	// elapse before a worker is added. Defaults to 1.
+	ScaleUpAfter int
+	// ScaleDownCooldown is how long pending must stay under LowWatermark
+	// before a worker is retired.
+	ScaleDownCooldown time.Duration
+	// HandlerLatencyThreshold, when > 0, is an additional scale-up trigger:
+	// a stream whose rolling average handler latency exceeds this threshold
+	// counts toward ScaleUpAfter the same way an over-HighWatermark pending
+	// count does, so a slow handler grows the pool even if pending hasn't
+	// built up yet. Zero disables the latency-based trigger.
+	HandlerLatencyThreshold time.Duration
+
+	// MaxBatchSize, when a BatchHandler is registered (see NewBatchConsumer),
+	// caps how many messages a single XREADGROUP/BatchHandler call contains.
+	// Defaults to ReadCount.
+	MaxBatchSize int64
+	// MaxBatchWait is the XREADGROUP BLOCK timeout used when a BatchHandler
+	// is registered. XREADGROUP returns as soon as any message is available,
+	// so a batch holds whatever was ready at that moment (up to
+	// MaxBatchSize); messages are not accumulated across reads. Defaults to
+	// BlockTime.
+	MaxBatchWait time.Duration
+}
+
+// buildClient constructs the redis.UniversalClient NewConsumer will use,
+// honoring cfg.Client as an escape hatch and cfg.Mode otherwise.
+func buildClient(cfg ConsumerConfig) (redis.UniversalClient, error) {
+	if cfg.Client != nil {
+		return cfg.Client, nil
+	}
+
+	switch cfg.Mode {
+	case Sentinel:
+		if cfg.MasterName == "" || len(cfg.SentinelAddrs) == 0 {
+			return nil, errors.New("sentinel mode requires MasterName and SentinelAddrs")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.SentinelAddrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.User,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        cfg.TLSConfig,
+		}), nil
+	case Cluster:
+		if len(cfg.ClusterAddrs) == 0 {
+			return nil, errors.New("cluster mode requires ClusterAddrs")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:         cfg.ClusterAddrs,
+			Username:      cfg.User,
+			Password:      cfg.Password,
+			RouteRandomly: cfg.RouteRandomly,
+			TLSConfig:     cfg.TLSConfig,
+		}), nil
+	default:
+		if cfg.Addr == "" {
+			return nil, errors.New("standalone mode requires Addr")
+		}
+		return redis.NewClient(&redis.Options{
+			Addr:      cfg.Addr,
+			Username:  cfg.User,
+			Password:  cfg.Password,
+			DB:        cfg.DB,
+			TLSConfig: cfg.TLSConfig,
+		}), nil
+	}
 }
 
 type Consumer struct {
 	cfg     ConsumerConfig
-	client  *redis.Client
-	handler Handler
-	wg      sync.WaitGroup
-	cancel  context.CancelFunc
-	started bool
-	startMu sync.Mutex
-	mu      sync.Mutex
+	client  redis.UniversalClient
+	handler ResultHandler
+	// batchHandler, when set (via NewBatchConsumer), takes over message
+	// delivery for every stream instead of handler.
+	batchHandler BatchHandler
+	wg           sync.WaitGroup
+	cancel       context.CancelFunc
+	started      bool
+	startMu      sync.Mutex
+	mu           sync.Mutex
 	// Tracking last poll time per stream
 	lastPollMu sync.Mutex
 	lastPoll   time.Time
+	// PEL reclaim/dead-letter counters, surfaced via the health endpoint
+	reclaimed    int64
+	deadLettered int64
+
+	registry *prometheus.Registry
+	metrics  *metrics
+
+	streamWorkersMu sync.Mutex
+	streamWorkers   map[string]*streamWorkers
+}
+
+// streamWorkers tracks the live worker pool and autoscaler state for one
+// stream.
+type streamWorkers struct {
+	mu           sync.Mutex
+	cancels      map[int]context.CancelFunc
+	nextID       int
+	highRun      int       // consecutive intervals pending (or latency) has exceeded its watermark
+	lowSince     time.Time // when pending most recently dropped under LowWatermark
+	current      int64     // atomic: number of live workers
+	readCount    int64     // atomic: current XREADGROUP Count for this stream
+	latencyNanos int64     // atomic: rolling average handler latency, in nanoseconds
+}
+
+// recordLatency folds d into sw's rolling average handler latency using a
+// simple exponential decay, so evaluateScale can react to handlers slowing
+// down even when the pending count hasn't caught up yet.
+func (sw *streamWorkers) recordLatency(d time.Duration) {
+	const alpha = 0.2 // weight given to the newest sample
+	for {
+		old := atomic.LoadInt64(&sw.latencyNanos)
+		next := old + int64(alpha*(float64(d)-float64(old)))
+		if atomic.CompareAndSwapInt64(&sw.latencyNanos, old, next) {
+			return
+		}
+	}
 }
 
 func NewConsumer(cfg ConsumerConfig, handler Handler) (*Consumer, error) {
 	if handler == nil {
 		return nil, errors.New("handler required")
 	}
+	return newConsumer(cfg, wrapHandler(cfg, handler))
+}
+
+// NewResultConsumer is like NewConsumer but takes a ResultHandler, giving
+// callers explicit Ack/Nack/Retry/DeadLetter control over each message
+// instead of Handler's coarse error-or-nil signature.
+func NewResultConsumer(cfg ConsumerConfig, handler ResultHandler) (*Consumer, error) {
+	if handler == nil {
+		return nil, errors.New("handler required")
+	}
+	return newConsumer(cfg, handler)
+}
+
+// NewBatchConsumer is like NewConsumer but registers a BatchHandler: each
+// XREADGROUP call's full slice of messages is delivered to handler in one
+// call, and every message it acks is acknowledged via a single pipelined
+// XACK instead of one round trip per message.
+func NewBatchConsumer(cfg ConsumerConfig, handler BatchHandler) (*Consumer, error) {
+	if handler == nil {
+		return nil, errors.New("handler required")
+	}
+	c, err := newConsumer(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.batchHandler = handler
+	if c.cfg.MaxBatchSize <= 0 {
+		c.cfg.MaxBatchSize = c.cfg.ReadCount
+	}
+	if c.cfg.MaxBatchWait <= 0 {
+		c.cfg.MaxBatchWait = c.cfg.BlockTime
+	}
+	return c, nil
+}
+
+// wrapHandler adapts a legacy Handler into a ResultHandler, preserving its
+// original semantics: an error nacks (leaving the message pending) and logs,
+// while success acks only when AckOnSuccess is set.
+func wrapHandler(cfg ConsumerConfig, handler Handler) ResultHandler {
+	return func(ctx context.Context, stream string, msg redis.XMessage) HandlerResult {
+		if err := handler(ctx, stream, msg); err != nil {
+			// Reason is set (not just logged here) so processMessage/processBatch
+			// both log it and count it toward handlerErrors.
+			return HandlerResult{Action: NackMessage, Reason: err.Error()}
+		}
+		if cfg.AckOnSuccess {
+			return HandlerResult{Action: AckMessage}
+		}
+		return HandlerResult{Action: NackMessage}
+	}
+}
+
+func newConsumer(cfg ConsumerConfig, handler ResultHandler) (*Consumer, error) {
 	if len(cfg.Streams) == 0 || cfg.Group == "" {
 		return nil, errors.New("streams and group are required")
 	}
@@ -67,18 +347,47 @@ func NewConsumer(cfg ConsumerConfig, handler Handler) (*Consumer, error) {
 	if cfg.Tracer == nil {
 		cfg.Tracer = otel.Tracer("redisconsumer")
 	}
+	if cfg.ReclaimInterval > 0 && cfg.MinIdleTime <= 0 {
+		cfg.MinIdleTime = 30 * time.Second
+	}
+	if cfg.MetricsSampleInterval <= 0 {
+		cfg.MetricsSampleInterval = 15 * time.Second
+	}
+	if cfg.MaxConcurrency > 0 {
+		if cfg.MinConcurrency <= 0 {
+			cfg.MinConcurrency = cfg.Concurrency
+		}
+		if cfg.ScaleInterval <= 0 {
+			cfg.ScaleInterval = 10 * time.Second
+		}
+		if cfg.ScaleUpAfter <= 0 {
+			cfg.ScaleUpAfter = 1
+		}
+	}
+
+	rdb, err := buildClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := cfg.MetricsRegistry
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
+
+	m, err := newMetrics(registry)
+	if err != nil {
+		return nil, fmt.Errorf("redisconsumer: register metrics: %w", err)
+	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.Addr,
-		Username: cfg.User,
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
 	c := &Consumer{
-		cfg:      cfg,
-		client:   rdb,
-		handler:  handler,
-		lastPoll: time.Now(),
+		cfg:           cfg,
+		client:        rdb,
+		handler:       handler,
+		lastPoll:      time.Now(),
+		registry:      registry,
+		metrics:       m,
+		streamWorkers: make(map[string]*streamWorkers),
 	}
 	return c, nil
 }
@@ -112,6 +421,14 @@ func (c *Consumer) Start(ctx context.Context) error {
 	for _, stream := range c.cfg.Streams {
 		c.wg.Add(1)
 		go c.consumeStream(ctx, stream)
+
+		if c.cfg.ReclaimInterval > 0 {
+			c.wg.Add(1)
+			go c.reclaimLoop(ctx, stream)
+		}
+
+		c.wg.Add(1)
+		go c.sampleMetricsLoop(ctx, stream)
 	}
 
 	return nil
@@ -120,26 +437,76 @@ func (c *Consumer) Start(ctx context.Context) error {
 func (c *Consumer) consumeStream(ctx context.Context, stream string) {
 	defer c.wg.Done()
 
-	// Create a separate goroutine pool for this stream
+	sw := &streamWorkers{cancels: make(map[int]context.CancelFunc)}
+	atomic.StoreInt64(&sw.readCount, c.cfg.ReadCount)
+
+	c.streamWorkersMu.Lock()
+	c.streamWorkers[stream] = sw
+	c.streamWorkersMu.Unlock()
+
+	// Create the initial goroutine pool for this stream
 	for i := 0; i < c.cfg.Concurrency; i++ {
+		c.spawnWorker(ctx, stream, sw)
+	}
+
+	if c.cfg.MaxConcurrency > 0 {
 		c.wg.Add(1)
-		go c.consumeLoop(ctx, stream, i)
+		go c.autoscaleLoop(ctx, stream, sw)
 	}
 }
 
-func (c *Consumer) consumeLoop(ctx context.Context, stream string, id int) {
+// spawnWorker starts one more consumeLoop goroutine for stream, tracked in
+// sw so the autoscaler can retire it later.
+func (c *Consumer) spawnWorker(ctx context.Context, stream string, sw *streamWorkers) {
+	sw.mu.Lock()
+	id := sw.nextID
+	sw.nextID++
+	wctx, cancel := context.WithCancel(ctx)
+	sw.cancels[id] = cancel
+	sw.mu.Unlock()
+
+	atomic.AddInt64(&sw.current, 1)
+	c.wg.Add(1)
+	go c.consumeLoop(wctx, stream, id, sw)
+}
+
+// retireWorker cancels and forgets one arbitrary worker for sw, returning
+// false if none are left to retire. current is decremented here, not in the
+// retired goroutine, so callers (e.g. evaluateScale's MinConcurrency check)
+// see the pool size change immediately instead of racing consumeLoop's own
+// exit.
+func (c *Consumer) retireWorker(sw *streamWorkers) bool {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	for id, cancel := range sw.cancels {
+		cancel()
+		delete(sw.cancels, id)
+		atomic.AddInt64(&sw.current, -1)
+		return true
+	}
+	return false
+}
+
+func (c *Consumer) consumeLoop(ctx context.Context, stream string, id int, sw *streamWorkers) {
 	defer c.wg.Done()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
+			count := atomic.LoadInt64(&sw.readCount)
+			block := c.cfg.BlockTime
+			if c.batchHandler != nil {
+				count = c.cfg.MaxBatchSize
+				block = c.cfg.MaxBatchWait
+			}
+
 			streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
 				Group:    c.cfg.Group,
 				Consumer: fmt.Sprintf("%s-%d", c.cfg.ConsumerName, id),
 				Streams:  []string{stream, ">"},
-				Count:    c.cfg.ReadCount,
-				Block:    c.cfg.BlockTime,
+				Count:    count,
+				Block:    block,
 			}).Result()
 
 			if err == redis.Nil {
@@ -157,16 +524,25 @@ func (c *Consumer) consumeLoop(ctx context.Context, stream string, id int) {
 			c.lastPollMu.Unlock()
 
 			for _, s := range streams {
+				c.metrics.messagesRead.WithLabelValues(stream, c.cfg.ConsumerName).Add(float64(len(s.Messages)))
+				if c.batchHandler != nil {
+					c.processBatch(ctx, stream, s.Messages, sw)
+					continue
+				}
 				for _, msg := range s.Messages {
-					c.processMessage(ctx, stream, msg)
+					c.processMessage(ctx, stream, msg, sw)
 				}
 			}
 		}
 	}
 }
 
-func (c *Consumer) processMessage(ctx context.Context, stream string, msg redis.XMessage) {
+// sw is the issuing worker's stream pool state, used to feed the handler's
+// latency back into the autoscaler; it is nil when called outside a
+// consumeLoop worker (e.g. from reclaimPending before a pool exists yet).
+func (c *Consumer) processMessage(ctx context.Context, stream string, msg redis.XMessage, sw *streamWorkers) {
 	tracer := c.cfg.Tracer
+	ctx = extractPropagatedContext(ctx, msg.Values)
 	hctx, span := tracer.Start(ctx, "redis.process_message",
 		trace.WithAttributes(
 			attribute.String("redis.stream", stream),
@@ -174,19 +550,406 @@ func (c *Consumer) processMessage(ctx context.Context, stream string, msg redis.
 		))
 	defer span.End()
 
-	// Call the user-defined handler
-	if err := c.handler(hctx, stream, msg); err != nil {
-		log.Printf("[redisconsumer] handler error stream=%s id=%s err=%v", stream, msg.ID, err)
+	consumerName := c.cfg.ConsumerName
+	c.metrics.messagesProcessed.WithLabelValues(stream, consumerName).Inc()
+
+	// Call the user-defined handler and act on its decision
+	start := time.Now()
+	result := c.handler(hctx, stream, msg)
+	latency := time.Since(start)
+	c.metrics.handlerLatency.WithLabelValues(stream, consumerName).Observe(latency.Seconds())
+	if sw != nil {
+		sw.recordLatency(latency)
+	}
+
+	switch result.Action {
+	case AckMessage:
+		c.ack(ctx, stream, msg.ID)
+		c.metrics.messagesAcked.WithLabelValues(stream, consumerName).Inc()
+	case RetryMessage:
+		c.retryMessage(ctx, stream, msg, result)
+	case DeadLetterMessage:
+		deliveries, _ := c.deliveryCount(ctx, stream, msg.ID)
+		c.deadLetter(ctx, stream, msg, result.Reason, deliveries)
+	case NackMessage:
+		fallthrough
+	default:
+		if result.Reason != "" {
+			log.Printf("[redisconsumer] handler nack stream=%s id=%s reason=%s", stream, msg.ID, result.Reason)
+			c.metrics.handlerErrors.WithLabelValues(stream, consumerName).Inc()
+		}
+		// Leave pending; the PEL reclaimer (if enabled) will redeliver it.
+	}
+}
+
+func (c *Consumer) ack(ctx context.Context, stream, id string) {
+	if err := c.client.XAck(ctx, stream, c.cfg.Group, id).Err(); err != nil {
+		log.Printf("[redisconsumer] ack error stream=%s id=%s err=%v", stream, id, err)
+	}
+}
+
+// retryMessage re-publishes msg onto stream with an incremented x-attempt
+// header after result.RetryAfter, then acks the original delivery so the
+// retry is the only outstanding copy.
+func (c *Consumer) retryMessage(ctx context.Context, stream string, msg redis.XMessage, result HandlerResult) {
+	if result.RetryAfter > 0 {
+		select {
+		case <-time.After(result.RetryAfter):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	attempt := int64(1)
+	if v, ok := msg.Values["x-attempt"]; ok {
+		if n, err := strconv.ParseInt(fmt.Sprint(v), 10, 64); err == nil {
+			attempt = n + 1
+		}
+	}
+	values := make(map[string]interface{}, len(msg.Values)+1)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["x-attempt"] = attempt
+
+	if _, err := c.client.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: values}).Result(); err != nil {
+		log.Printf("[redisconsumer] retry re-add error stream=%s id=%s: %v", stream, msg.ID, err)
+		return
+	}
+	c.ack(ctx, stream, msg.ID)
+}
+
+// processBatch delivers an entire XREADGROUP batch to c.batchHandler in one
+// call and acknowledges every Ack'd message with a single pipelined XACK.
+// sw behaves as in processMessage: nil when called outside a consumeLoop
+// worker.
+func (c *Consumer) processBatch(ctx context.Context, stream string, msgs []redis.XMessage, sw *streamWorkers) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	tracer := c.cfg.Tracer
+	hctx, span := tracer.Start(ctx, "redis.process_batch",
+		trace.WithAttributes(
+			attribute.String("redis.stream", stream),
+			attribute.Int("redis.batch_size", len(msgs)),
+		))
+	defer span.End()
+
+	consumerName := c.cfg.ConsumerName
+	c.metrics.messagesProcessed.WithLabelValues(stream, consumerName).Add(float64(len(msgs)))
+
+	start := time.Now()
+	results := c.batchHandler(hctx, stream, msgs)
+	latency := time.Since(start)
+	c.metrics.handlerLatency.WithLabelValues(stream, consumerName).Observe(latency.Seconds())
+	if sw != nil {
+		sw.recordLatency(latency)
+	}
+
+	if len(results) != len(msgs) {
+		log.Printf("[redisconsumer] batch handler returned %d results for %d messages stream=%s", len(results), len(msgs), stream)
+	}
+
+	ackIDs := make([]string, 0, len(msgs))
+	for i, msg := range msgs {
+		result := HandlerResult{Action: NackMessage}
+		if i < len(results) {
+			result = results[i]
+		}
+
+		switch result.Action {
+		case AckMessage:
+			ackIDs = append(ackIDs, msg.ID)
+		case RetryMessage:
+			c.retryMessage(ctx, stream, msg, result)
+		case DeadLetterMessage:
+			deliveries, _ := c.deliveryCount(ctx, stream, msg.ID)
+			c.deadLetter(ctx, stream, msg, result.Reason, deliveries)
+		case NackMessage:
+			fallthrough
+		default:
+			if result.Reason != "" {
+				log.Printf("[redisconsumer] handler nack stream=%s id=%s reason=%s", stream, msg.ID, result.Reason)
+				c.metrics.handlerErrors.WithLabelValues(stream, consumerName).Inc()
+			}
+		}
+	}
+
+	if len(ackIDs) == 0 {
 		return
 	}
+	if err := c.client.XAck(ctx, stream, c.cfg.Group, ackIDs...).Err(); err != nil {
+		log.Printf("[redisconsumer] batch ack error stream=%s count=%d err=%v", stream, len(ackIDs), err)
+		return
+	}
+	c.metrics.messagesAcked.WithLabelValues(stream, consumerName).Add(float64(len(ackIDs)))
+}
+
+// autoscaleLoop periodically evaluates a stream's pending count and grows
+// or shrinks its worker pool between MinConcurrency and MaxConcurrency.
+func (c *Consumer) autoscaleLoop(ctx context.Context, stream string, sw *streamWorkers) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.cfg.ScaleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.evaluateScale(ctx, stream, sw)
+		}
+	}
+}
+
+func (c *Consumer) evaluateScale(ctx context.Context, stream string, sw *streamWorkers) {
+	pending, err := c.client.XPending(ctx, stream, c.cfg.Group).Result()
+	if err != nil {
+		log.Printf("[redisconsumer] autoscale: xpending error stream=%s: %v", stream, err)
+		return
+	}
+	count := pending.Count
+	current := atomic.LoadInt64(&sw.current)
+	latency := time.Duration(atomic.LoadInt64(&sw.latencyNanos))
+	slowHandlers := c.cfg.HandlerLatencyThreshold > 0 && latency > c.cfg.HandlerLatencyThreshold
+
+	switch {
+	case count > c.cfg.HighWatermark || slowHandlers:
+		// Over water: count this interval and grow the pool once enough
+		// consecutive intervals have confirmed the backlog (or the handler
+		// slowdown) is real.
+		sw.mu.Lock()
+		sw.highRun++
+		run := sw.highRun
+		sw.lowSince = time.Time{}
+		sw.mu.Unlock()
+
+		if run >= c.cfg.ScaleUpAfter && current < int64(c.cfg.MaxConcurrency) {
+			c.spawnWorker(ctx, stream, sw)
+			c.metrics.scaleEvents.WithLabelValues(stream, c.cfg.ConsumerName, "up").Inc()
+			log.Printf("[redisconsumer] autoscale: stream=%s scaled up to %d workers (pending=%d, latency=%s)", stream, current+1, count, latency)
+			sw.mu.Lock()
+			sw.highRun = 0
+			sw.mu.Unlock()
+		}
+
+		// Handlers are falling behind; fetch smaller batches so a slow
+		// downstream doesn't sit on a large, already-claimed batch.
+		atomic.StoreInt64(&sw.readCount, maxInt64(1, c.cfg.ReadCount/2))
+
+	case count < c.cfg.LowWatermark:
+		sw.mu.Lock()
+		sw.highRun = 0
+		if sw.lowSince.IsZero() {
+			sw.lowSince = time.Now()
+		}
+		since := sw.lowSince
+		sw.mu.Unlock()
+
+		if time.Since(since) > c.cfg.ScaleDownCooldown && current > int64(c.cfg.MinConcurrency) {
+			if c.retireWorker(sw) {
+				c.metrics.scaleEvents.WithLabelValues(stream, c.cfg.ConsumerName, "down").Inc()
+				log.Printf("[redisconsumer] autoscale: stream=%s scaled down to %d workers (pending=%d)", stream, current-1, count)
+				sw.mu.Lock()
+				sw.lowSince = time.Now()
+				sw.mu.Unlock()
+			}
+		}
+
+		atomic.StoreInt64(&sw.readCount, c.cfg.ReadCount)
+
+	default:
+		sw.mu.Lock()
+		sw.highRun = 0
+		sw.lowSince = time.Time{}
+		sw.mu.Unlock()
+		atomic.StoreInt64(&sw.readCount, c.cfg.ReadCount)
+	}
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// reclaimLoop periodically sweeps stream's pending entries list, taking
+// over messages abandoned by consumers that crashed or stalled.
+func (c *Consumer) reclaimLoop(ctx context.Context, stream string) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.cfg.ReclaimInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.reclaimPending(ctx, stream)
+		}
+	}
+}
+
+func (c *Consumer) reclaimPending(ctx context.Context, stream string) {
+	c.streamWorkersMu.Lock()
+	sw := c.streamWorkers[stream]
+	c.streamWorkersMu.Unlock()
+
+	// Reclaimed messages go to the BatchHandler as one batch, so keep each
+	// claim within MaxBatchSize in batch mode.
+	count := c.cfg.ReadCount
+	if c.batchHandler != nil {
+		count = c.cfg.MaxBatchSize
+	}
 
-	if c.cfg.AckOnSuccess {
-		if err := c.client.XAck(ctx, stream, c.cfg.Group, msg.ID).Err(); err != nil {
-			log.Printf("[redisconsumer] ack error stream=%s id=%s err=%v", stream, msg.ID, err)
+	start := "0-0"
+	for {
+		msgs, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    c.cfg.Group,
+			Consumer: c.cfg.ConsumerName,
+			MinIdle:  c.cfg.MinIdleTime,
+			Start:    start,
+			Count:    count,
+		}).Result()
+		if err != nil {
+			log.Printf("[redisconsumer] reclaim error stream=%s: %v", stream, err)
+			return
+		}
+
+		deliverable := msgs[:0]
+		for _, msg := range msgs {
+			atomic.AddInt64(&c.reclaimed, 1)
+			c.metrics.reclaimed.WithLabelValues(stream, c.cfg.ConsumerName).Inc()
+			if c.cfg.MaxDeliveries > 0 {
+				if deliveries, ok := c.deliveryCount(ctx, stream, msg.ID); ok && deliveries > c.cfg.MaxDeliveries {
+					c.deadLetter(ctx, stream, msg, "max deliveries exceeded", deliveries)
+					continue
+				}
+			}
+			deliverable = append(deliverable, msg)
+		}
+
+		// Route through whichever handler this Consumer was built with —
+		// c.handler is nil for a NewBatchConsumer, so reclaimed messages
+		// must go through processBatch, not processMessage, or this panics.
+		if c.batchHandler != nil {
+			c.processBatch(ctx, stream, deliverable, sw)
+		} else {
+			for _, msg := range deliverable {
+				c.processMessage(ctx, stream, msg, sw)
+			}
+		}
+
+		if next == "0-0" || len(msgs) == 0 {
+			return
+		}
+		start = next
+	}
+}
+
+// sampleMetricsLoop periodically refreshes the pending-count and
+// consumer-lag gauges for a stream.
+func (c *Consumer) sampleMetricsLoop(ctx context.Context, stream string) {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.cfg.MetricsSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.sampleStreamMetrics(ctx, stream)
 		}
 	}
 }
 
+func (c *Consumer) sampleStreamMetrics(ctx context.Context, stream string) {
+	consumerName := c.cfg.ConsumerName
+
+	if pending, err := c.client.XPending(ctx, stream, c.cfg.Group).Result(); err != nil {
+		log.Printf("[redisconsumer] metrics: xpending error stream=%s: %v", stream, err)
+	} else {
+		c.metrics.pending.WithLabelValues(stream, consumerName).Set(float64(pending.Count))
+	}
+
+	info, err := c.client.XInfoStream(ctx, stream).Result()
+	if err != nil {
+		log.Printf("[redisconsumer] metrics: xinfo stream error stream=%s: %v", stream, err)
+		return
+	}
+	groups, err := c.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		log.Printf("[redisconsumer] metrics: xinfo groups error stream=%s: %v", stream, err)
+		return
+	}
+	for _, g := range groups {
+		if g.Name != c.cfg.Group {
+			continue
+		}
+		lag := streamIDMillis(info.LastGeneratedID) - streamIDMillis(g.LastDeliveredID)
+		c.metrics.lagMillis.WithLabelValues(stream, consumerName).Set(float64(lag))
+	}
+}
+
+// streamIDMillis extracts the millisecond-time component of a Redis stream
+// ID ("<ms>-<seq>"), which is enough for an approximate lag comparison.
+func streamIDMillis(id string) int64 {
+	ms, _, _ := strings.Cut(id, "-")
+	n, _ := strconv.ParseInt(ms, 10, 64)
+	return n
+}
+
+// deliveryCount looks up how many times a pending entry has been delivered.
+func (c *Consumer) deliveryCount(ctx context.Context, stream, id string) (int64, bool) {
+	entries, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  c.cfg.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(entries) == 0 {
+		return 0, false
+	}
+	return entries[0].RetryCount, true
+}
+
+// deadLetter routes a poison message to cfg.DeadLetterStream, preserving its
+// original ID, stream, error reason, and delivery count, then acks the
+// original so a blocked consumer group can keep making progress.
+func (c *Consumer) deadLetter(ctx context.Context, stream string, msg redis.XMessage, reason string, deliveries int64) {
+	if c.cfg.DeadLetterStream == "" {
+		return
+	}
+
+	values := map[string]interface{}{
+		"original_id":     msg.ID,
+		"original_stream": stream,
+		"error":           reason,
+		"deliveries":      deliveries,
+	}
+	for k, v := range msg.Values {
+		values["data_"+k] = v
+	}
+
+	if _, err := c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: c.cfg.DeadLetterStream,
+		Values: values,
+	}).Result(); err != nil {
+		log.Printf("[redisconsumer] dead-letter add error stream=%s id=%s: %v", stream, msg.ID, err)
+		return
+	}
+
+	if err := c.client.XAck(ctx, stream, c.cfg.Group, msg.ID).Err(); err != nil {
+		log.Printf("[redisconsumer] dead-letter ack error stream=%s id=%s: %v", stream, msg.ID, err)
+		return
+	}
+
+	atomic.AddInt64(&c.deadLettered, 1)
+	c.metrics.deadLettered.WithLabelValues(stream, c.cfg.ConsumerName).Inc()
+}
+
 func (c *Consumer) Stop(ctx context.Context) error {
 	if c.cancel != nil {
 		c.cancel()
@@ -220,9 +983,15 @@ func (c *Consumer) serveHealth(ctx context.Context, addr string) {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 			return
 		}
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("OK"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":        "OK",
+			"reclaimed":     atomic.LoadInt64(&c.reclaimed),
+			"dead_lettered": atomic.LoadInt64(&c.deadLettered),
+		})
 	})
+	mux.Handle("/metrics", promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{}))
 	srv := &http.Server{
 		Addr:    addr,
 		Handler: mux,
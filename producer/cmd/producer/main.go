@@ -9,9 +9,54 @@ import (
 	"os/signal"
 	"time"
 
+	"producer/internal/redisproducer"
+
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// initTracer sets up an OTLP/gRPC exporter. The endpoint is read from
+// OTEL_EXPORTER_OTLP_ENDPOINT as a plain host:port (no URL scheme),
+// defaulting to the standard local collector port.
+func initTracer(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "localhost:4317"
+	}
+
+	exp, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("redis-producer"),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	// Without this, the global propagator defaults to a no-op composite and
+	// redisproducer.Inject never writes a traceparent field, so the consumer
+	// has nothing to extract and spans never join into one trace.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp, nil
+}
+
 func main() {
 	var (
 		redisAddr   = flag.String("redis-addr", "localhost:6380", "Redis server address")
@@ -22,6 +67,18 @@ func main() {
 	)
 	flag.Parse()
 
+	ctx := context.Background()
+	tp, err := initTracer(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Printf("Error shutting down tracer provider: %v", err)
+		}
+	}()
+	tracer := tp.Tracer("redis-producer")
+
 	// Connect to Redis
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     *redisAddr,
@@ -31,7 +88,6 @@ func main() {
 	defer rdb.Close()
 
 	// Test Redis connection
-	ctx := context.Background()
 	if err := rdb.Ping(ctx).Err(); err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
@@ -66,11 +122,18 @@ func main() {
 				"timestamp": time.Now().Format(time.RFC3339),
 			}
 
+			// Start a span for this publish and inject it into the message
+			// so the consumer can continue the same trace.
+			pctx, span := tracer.Start(ctx, "redis.publish",
+				trace.WithAttributes(attribute.String("redis.stream", *stream)))
+			redisproducer.Inject(pctx, values)
+
 			// Publish message to stream
-			msgID, err := rdb.XAdd(ctx, &redis.XAddArgs{
+			msgID, err := rdb.XAdd(pctx, &redis.XAddArgs{
 				Stream: *stream,
 				Values: values,
 			}).Result()
+			span.End()
 
 			if err != nil {
 				log.Printf("Error publishing message: %v", err)
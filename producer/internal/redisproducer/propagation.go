@@ -0,0 +1,42 @@
+// Package redisproducer holds small helpers shared by producer commands for
+// publishing onto Redis streams.
+package redisproducer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// valuesCarrier adapts a Redis XADD values map to otel's TextMapCarrier so
+// the active span context can be injected into a stream message as ordinary
+// fields for a consumer to extract later.
+type valuesCarrier map[string]interface{}
+
+func (c valuesCarrier) Get(key string) string {
+	v, ok := c[key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func (c valuesCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c valuesCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Inject writes the span context carried by ctx into values using the W3C
+// TraceContext format (traceparent/tracestate fields), so a consumer can
+// later extract it via redisconsumer and continue the same trace.
+func Inject(ctx context.Context, values map[string]interface{}) {
+	otel.GetTextMapPropagator().Inject(ctx, valuesCarrier(values))
+}
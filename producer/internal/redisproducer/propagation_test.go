@@ -0,0 +1,48 @@
+package redisproducer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// This exercises the exact failure mode a prior review caught: Inject is a
+// silent no-op unless something has called otel.SetTextMapPropagator, since
+// the SDK's default global propagator is an empty composite.
+func TestInjectWritesTraceparent(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	traceID, err := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	if err != nil {
+		t.Fatalf("TraceIDFromHex: %v", err)
+	}
+	spanID, err := trace.SpanIDFromHex("0102030405060708")
+	if err != nil {
+		t.Fatalf("SpanIDFromHex: %v", err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	values := map[string]interface{}{}
+	Inject(ctx, values)
+
+	traceparent, ok := values["traceparent"].(string)
+	if !ok || traceparent == "" {
+		t.Fatalf("expected a traceparent field injected into values, got %v", values)
+	}
+	if !strings.Contains(traceparent, traceID.String()) {
+		t.Fatalf("traceparent %q missing trace id %s", traceparent, traceID.String())
+	}
+	if !strings.Contains(traceparent, spanID.String()) {
+		t.Fatalf("traceparent %q missing span id %s", traceparent, spanID.String())
+	}
+}